@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/sponkurtus2/goBooksBold/internal/bionic"
+)
+
+// runConvert implements the `convert <input.pdf|-> <output.pdf|->`
+// subcommand: the same bionic-reading transformation as the HTTP server,
+// run once and scriptable in a shell pipeline.
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fixationFlag := fs.String("fixation", "medium", "bionic-reading fixation ratio: low|medium|high")
+	fs.Parse(args)
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		return fmt.Errorf("usage: convert <input.pdf|-> <output.pdf|->")
+	}
+	inputPath, outputPath := rest[0], rest[1]
+
+	in, err := openInput(inputPath)
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer in.Close()
+
+	// ledongthuc/pdf needs a real file to seek within, so stdin is
+	// staged through a temp file just like an HTTP upload is.
+	tmpFile, err := os.CreateTemp("", "convert_*.pdf")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, in); err != nil {
+		return fmt.Errorf("staging input: %w", err)
+	}
+
+	f, rr, err := pdf.Open(tmpFile.Name())
+	if err != nil {
+		return fmt.Errorf("opening pdf: %w", err)
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	pageContent, err := bionic.ReadPdfContent(ctx, rr)
+	if err != nil {
+		return fmt.Errorf("reading pdf content: %w", err)
+	}
+
+	// Render to an in-memory buffer first: outputPath may be an existing
+	// file the caller cares about, and it shouldn't be truncated until a
+	// full PDF is ready to replace it.
+	var buf bytes.Buffer
+	fixation := bionic.NewFixationConfig(bionic.ParseFixationLevel(*fixationFlag))
+	if err := bionic.RenderPdf(ctx, bionic.NewBionicPdfWriter(*pageContent, fixation), 1, &buf, nil); err != nil {
+		return fmt.Errorf("rendering pdf: %w", err)
+	}
+
+	out, err := openOutput(outputPath)
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := out.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+
+	return nil
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,118 @@
+// Package bionic holds the PDF extraction and generation pipeline shared
+// by goBooksBold's HTTP server and its convert CLI: reading the plain
+// text out of a PDF, and writing a bionic-reading (or searchable hOCR)
+// PDF back.
+package bionic
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+//go:embed assets/fonts/georgia.ttf.zlib
+var georgiaFontZlib []byte
+
+//go:embed assets/fonts/georgiab.ttf.zlib
+var georgiaBoldFontZlib []byte
+
+var georgiaFontBytes []byte
+var georgiaBoldFontBytes []byte
+
+func init() {
+	var err error
+	georgiaFontBytes, err = decompressFont(georgiaFontZlib)
+	if err != nil {
+		log.Fatalf("Failed to decompress embedded font: %v", err)
+	}
+	georgiaBoldFontBytes, err = decompressFont(georgiaBoldFontZlib)
+	if err != nil {
+		log.Fatalf("Failed to decompress embedded bold font: %v", err)
+	}
+}
+
+// decompressFont inflates a zlib-compressed TTF embedded via go:embed so it
+// can be handed to gofpdf.AddUTF8FontFromBytes without touching disk.
+func decompressFont(compressed []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func toUTF8(text string) (string, error) {
+	if utf8.ValidString(text) {
+		return text, nil
+	}
+
+	reader := transform.NewReader(strings.NewReader(text), charmap.ISO8859_1.NewDecoder())
+	buf := new(bytes.Buffer)
+	_, err := buf.ReadFrom(reader)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func normalizeSpaces(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			result.WriteRune(' ')
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(result.String())
+}
+
+// ReadPdfContent extracts and normalizes the plain text of every page
+// into a builder scoped to this call, so concurrent requests never share
+// state. It aborts early if ctx is cancelled or its deadline expires.
+func ReadPdfContent(ctx context.Context, reader *pdf.Reader) (*strings.Builder, error) {
+	var content strings.Builder
+	totalPages := reader.NumPage()
+
+	for pageNum := 1; pageNum <= totalPages; pageNum++ {
+		if err := ctx.Err(); err != nil {
+			return &content, err
+		}
+
+		page := reader.Page(pageNum)
+		if page.V.IsNull() {
+			continue
+		}
+
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			fmt.Printf("Error on page num %d, %v\n", pageNum, err)
+			continue
+		}
+
+		utf8Text, err := toUTF8(text)
+		if err != nil {
+			log.Printf("Error converting to UTF-8 on page %d: %v\n", pageNum, err)
+			continue
+		}
+
+		normalizedText := normalizeSpaces(utf8Text)
+		pdfFormatedContent := fmt.Sprintf("Page -> %d \n%s\n", pageNum, normalizedText)
+		content.WriteString(pdfFormatedContent)
+
+	}
+
+	return &content, nil
+}
@@ -0,0 +1,80 @@
+package bionic
+
+import "testing"
+
+func TestFixationConfigBoldLen(t *testing.T) {
+	cfg := NewFixationConfig(FixationMedium)
+
+	cases := []struct {
+		word string
+		want int
+	}{
+		{"a", 1},
+		{"cat", 1},
+		{"house", 2},
+		{"reading", 3},
+		{"beautiful", 4}, // 9 runes * 0.4 rounded
+	}
+
+	for _, c := range cases {
+		if got := cfg.BoldLen(c.word); got != c.want {
+			t.Errorf("BoldLen(%q) = %d, want %d", c.word, got, c.want)
+		}
+	}
+}
+
+func TestFixationConfigRatioScalesWithLevel(t *testing.T) {
+	word := "extraordinary" // long enough to hit the ratio branch
+	low := NewFixationConfig(FixationLow).BoldLen(word)
+	medium := NewFixationConfig(FixationMedium).BoldLen(word)
+	high := NewFixationConfig(FixationHigh).BoldLen(word)
+
+	if !(low <= medium && medium <= high) {
+		t.Errorf("expected low <= medium <= high, got low=%d medium=%d high=%d", low, medium, high)
+	}
+}
+
+func TestBoldByteLenMultibyteScripts(t *testing.T) {
+	cases := []struct {
+		name string
+		word string
+		n    int
+	}{
+		{"accented latin", "café", 2},
+		{"spanish enye", "mañana", 3},
+		{"cyrillic", "привет", 2},
+		{"combining mark", "éclair", 1}, // e + combining acute should stay together
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			splitAt := boldByteLen(c.word, c.n)
+
+			if splitAt < 0 || splitAt > len(c.word) {
+				t.Fatalf("boldByteLen(%q, %d) = %d, out of range", c.word, c.n, splitAt)
+			}
+
+			boldPart := c.word[:splitAt]
+			restPart := c.word[splitAt:]
+
+			if !isValidUTF8Boundary(c.word, splitAt) {
+				t.Fatalf("boldByteLen(%q, %d) = %d lands mid-rune", c.word, c.n, splitAt)
+			}
+			if boldPart+restPart != c.word {
+				t.Fatalf("split doesn't reassemble to original word: %q + %q != %q", boldPart, restPart, c.word)
+			}
+		})
+	}
+}
+
+func isValidUTF8Boundary(s string, offset int) bool {
+	for i := range s {
+		if i == offset {
+			return true
+		}
+		if i > offset {
+			return false
+		}
+	}
+	return offset == len(s)
+}
@@ -0,0 +1,263 @@
+package bionic
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	xhtml "golang.org/x/net/html"
+)
+
+// assumedPageWidthInches is the physical page width hOCR pixel
+// coordinates are assumed to have been scanned at. hOCR carries no unit
+// information of its own, so this is the same approximation most
+// searchable-PDF tools make for letter/A4-ish book scans.
+const assumedPageWidthInches = 5.0
+
+var bboxRe = regexp.MustCompile(`bbox (\d+) (\d+) (\d+) (\d+)`)
+
+// HocrWord is a single ocrx_word span: its text and its bounding box in
+// source image pixels.
+type HocrWord struct {
+	Text           string
+	X0, Y0, X1, Y1 float64
+}
+
+// HocrPage is one ocr_page: its words and the pixel dimensions of the
+// page image it was scanned from.
+type HocrPage struct {
+	Words             []HocrWord
+	WidthPx, HeightPx float64
+}
+
+// HocrPageImage is the page image uploaded alongside an hOCR page,
+// matched to it by position.
+type HocrPageImage struct {
+	ContentType string
+	Bytes       []byte
+}
+
+// ParseHocr walks an hOCR document (standard XHTML with ocr_page,
+// ocr_line and ocrx_word classes) and returns one HocrPage per ocr_page
+// element, in document order.
+func ParseHocr(r io.Reader) ([]HocrPage, error) {
+	doc, err := xhtml.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing hOCR: %w", err)
+	}
+
+	var pages []HocrPage
+	var current *HocrPage
+	var walkErr error
+
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if walkErr != nil {
+			return
+		}
+
+		if n.Type == xhtml.ElementNode {
+			class := nodeAttr(n, "class")
+			title := nodeAttr(n, "title")
+
+			switch {
+			case hasClass(class, "ocr_page"):
+				width, height, ok := pageDimensions(title)
+				if !ok {
+					walkErr = fmt.Errorf("ocr_page %d: missing or malformed bbox in title %q", len(pages)+1, title)
+					return
+				}
+				pages = append(pages, HocrPage{WidthPx: width, HeightPx: height})
+				current = &pages[len(pages)-1]
+			case hasClass(class, "ocrx_word"):
+				if current != nil {
+					if x0, y0, x1, y1, ok := parseBBox(title); ok {
+						current.Words = append(current.Words, HocrWord{
+							Text: html.UnescapeString(collectText(n)),
+							X0:   x0, Y0: y0, X1: x1, Y1: y1,
+						})
+					}
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+			if walkErr != nil {
+				return
+			}
+		}
+	}
+	walk(doc)
+
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	return pages, nil
+}
+
+func nodeAttr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(classAttr, want string) bool {
+	for _, c := range strings.Fields(classAttr) {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
+
+func collectText(n *xhtml.Node) string {
+	var sb strings.Builder
+
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if n.Type == xhtml.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return strings.TrimSpace(sb.String())
+}
+
+func parseBBox(title string) (x0, y0, x1, y1 float64, ok bool) {
+	m := bboxRe.FindStringSubmatch(title)
+	if m == nil {
+		return 0, 0, 0, 0, false
+	}
+
+	x0, _ = strconv.ParseFloat(m[1], 64)
+	y0, _ = strconv.ParseFloat(m[2], 64)
+	x1, _ = strconv.ParseFloat(m[3], 64)
+	y1, _ = strconv.ParseFloat(m[4], 64)
+	return x0, y0, x1, y1, true
+}
+
+// pageDimensions returns an ocr_page's pixel width/height from its bbox,
+// and false if the title has no bbox or it parses to a non-positive size
+// — either of which would make newPageGeometry divide by zero and emit a
+// PDF with a NaN/Inf MediaBox.
+func pageDimensions(title string) (width, height float64, ok bool) {
+	_, _, x1, y1, ok := parseBBox(title)
+	if !ok || x1 <= 0 || y1 <= 0 {
+		return 0, 0, false
+	}
+	return x1, y1, true
+}
+
+// pageGeometry converts hOCR pixel coordinates to PDF points for a page
+// scanned at assumedPageWidthInches wide.
+type pageGeometry struct {
+	dpi float64
+}
+
+func newPageGeometry(widthPx float64) pageGeometry {
+	return pageGeometry{dpi: widthPx / assumedPageWidthInches}
+}
+
+func (g pageGeometry) pxToPt(px float64) float64 {
+	return px / g.dpi * 72
+}
+
+// HocrPdfWriter implements Pdfer: each AddPage call places the next page
+// image at true size, then draws its OCR words on top in invisible text
+// so the page is selectable/searchable without changing how it looks.
+type HocrPdfWriter struct {
+	fpdf   *gofpdf.Fpdf
+	pages  []HocrPage
+	images []HocrPageImage
+	next   int
+}
+
+// NewHocrPdfWriter builds a HocrPdfWriter over pages and their matching
+// page images (by position).
+func NewHocrPdfWriter(pages []HocrPage, images []HocrPageImage) *HocrPdfWriter {
+	return &HocrPdfWriter{
+		fpdf:   gofpdf.New("P", "pt", "A4", ""),
+		pages:  pages,
+		images: images,
+	}
+}
+
+func (h *HocrPdfWriter) Setup() {
+	h.fpdf.SetMargins(0, 0, 0)
+	h.fpdf.AddUTF8FontFromBytes("georgia", "", georgiaFontBytes)
+	h.fpdf.SetFont("georgia", "", 10)
+}
+
+func (h *HocrPdfWriter) AddPage() {
+	if h.next >= len(h.pages) {
+		return
+	}
+	page := h.pages[h.next]
+	img := h.images[h.next]
+	h.next++
+
+	geom := newPageGeometry(page.WidthPx)
+	pageWidthPt := geom.pxToPt(page.WidthPx)
+	pageHeightPt := geom.pxToPt(page.HeightPx)
+
+	h.fpdf.AddPageFormat("P", gofpdf.SizeType{Wd: pageWidthPt, Ht: pageHeightPt})
+
+	imgName := fmt.Sprintf("page-%d", h.next)
+	imgOpts := gofpdf.ImageOptions{ImageType: imageTypeFromContentType(img.ContentType)}
+	h.fpdf.RegisterImageOptionsReader(imgName, imgOpts, bytes.NewReader(img.Bytes))
+	h.fpdf.ImageOptions(imgName, 0, 0, pageWidthPt, pageHeightPt, false, imgOpts, 0, "")
+
+	for _, word := range page.Words {
+		h.writeInvisibleWord(word, geom)
+	}
+}
+
+// writeInvisibleWord sizes the font so the word's rendered width matches
+// its hOCR bounding box, positions it at the box's bottom-left corner,
+// and writes it at zero opacity: present for text selection/search, but
+// not visible over the page image underneath.
+func (h *HocrPdfWriter) writeInvisibleWord(word HocrWord, geom pageGeometry) {
+	if word.Text == "" {
+		return
+	}
+
+	h.fpdf.SetFontSize(10)
+	targetWidth := geom.pxToPt(word.X1 - word.X0)
+	if actualWidth := h.fpdf.GetStringWidth(word.Text); actualWidth > 0 {
+		h.fpdf.SetFontSize(10 * targetWidth / actualWidth)
+	}
+
+	h.fpdf.SetXY(geom.pxToPt(word.X0), geom.pxToPt(word.Y1))
+	h.fpdf.SetAlpha(0, "Normal")
+	h.fpdf.Write(0, word.Text)
+	h.fpdf.SetAlpha(1, "Normal")
+}
+
+func (h *HocrPdfWriter) Save(out io.Writer) error {
+	return h.fpdf.Output(out)
+}
+
+func imageTypeFromContentType(contentType string) string {
+	switch contentType {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg", "image/jpg":
+		return "JPG"
+	default:
+		return "PNG"
+	}
+}
@@ -0,0 +1,32 @@
+package bionic
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHocrRejectsMissingPageBBox(t *testing.T) {
+	const doc = `<html><body>
+		<div class="ocr_page" title="image book.png">
+			<span class="ocrx_word" title="bbox 10 20 30 40">hello</span>
+		</div>
+	</body></html>`
+
+	_, err := ParseHocr(strings.NewReader(doc))
+	if err == nil {
+		t.Fatal("expected an error for an ocr_page with no bbox in its title, got nil")
+	}
+}
+
+func TestParseHocrRejectsZeroSizedPageBBox(t *testing.T) {
+	const doc = `<html><body>
+		<div class="ocr_page" title="bbox 0 0 0 0">
+			<span class="ocrx_word" title="bbox 10 20 30 40">hello</span>
+		</div>
+	</body></html>`
+
+	_, err := ParseHocr(strings.NewReader(doc))
+	if err == nil {
+		t.Fatal("expected an error for an ocr_page with a zero-sized bbox, got nil")
+	}
+}
@@ -0,0 +1,121 @@
+package bionic
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Pdfer is implemented by each PDF generation strategy this package
+// supports: bolding plain text extracted from a regular PDF
+// (BionicPdfWriter), and overlaying invisible OCR text on top of scanned
+// page images (HocrPdfWriter, see hocr.go). RenderPdf drives either one
+// through the same sequence so callers don't need to know which mode
+// they're running.
+type Pdfer interface {
+	Setup()
+	AddPage()
+	Save(w io.Writer) error
+}
+
+// RenderPdf runs p through Setup, one AddPage per page, then Save directly
+// into out, so a large PDF is streamed rather than buffered whole in
+// memory. onProgress, if non-nil, is called after each AddPage with the
+// page just completed and the total page count. It aborts early if ctx
+// is cancelled or its deadline expires.
+func RenderPdf(ctx context.Context, p Pdfer, pages int, out io.Writer, onProgress func(current, total int)) error {
+	p.Setup()
+	for i := 0; i < pages; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.AddPage()
+		if onProgress != nil {
+			onProgress(i+1, pages)
+		}
+	}
+
+	return p.Save(out)
+}
+
+// BionicPdfWriter renders content with the first letters of each word
+// bolded (the "bionic reading" effect), auto-paginating as gofpdf sees
+// fit. It has exactly one logical page of content, so RenderPdf only
+// ever calls AddPage once for it.
+type BionicPdfWriter struct {
+	fpdf     *gofpdf.Fpdf
+	content  strings.Builder
+	fixation FixationConfig
+}
+
+// NewBionicPdfWriter builds a BionicPdfWriter over content, bolding each
+// word's fixation point according to fixation.
+func NewBionicPdfWriter(content strings.Builder, fixation FixationConfig) *BionicPdfWriter {
+	return &BionicPdfWriter{
+		fpdf:     gofpdf.New("P", "mm", "A4", ""),
+		content:  content,
+		fixation: fixation,
+	}
+}
+
+func (b *BionicPdfWriter) Setup() {
+	b.fpdf.SetMargins(20, 20, 20)
+
+	b.fpdf.AddUTF8FontFromBytes("georgia", "", georgiaFontBytes)
+	b.fpdf.AddUTF8FontFromBytes("georgiab", "B", georgiaBoldFontBytes)
+
+	b.fpdf.SetFont("georgia", "", 12)
+
+	b.fpdf.SetHeaderFunc(func() {
+		b.fpdf.SetFont("georgia", "", 12)
+	})
+}
+
+func (b *BionicPdfWriter) AddPage() {
+	b.fpdf.AddPage()
+
+	text := b.content.String()
+	paragraphs := strings.Split(text, "\n\n")
+	for _, paragraph := range paragraphs {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for _, line := range lines {
+			if line == "" {
+				continue
+			}
+
+			words := strings.Fields(line)
+			for i, word := range words {
+				if len(word) == 0 {
+					continue
+				}
+
+				if isStopword(word) || isPunctuationOnly(word) {
+					b.fpdf.SetFont("georgia", "", 12)
+					b.fpdf.Write(5, word)
+				} else {
+					splitAt := boldByteLen(word, b.fixation.BoldLen(word))
+					boldPart := word[:splitAt]
+					restPart := word[splitAt:]
+
+					b.fpdf.SetFont("georgiab", "B", 12)
+					b.fpdf.Write(5, boldPart)
+
+					b.fpdf.SetFont("georgia", "", 12)
+					b.fpdf.Write(5, restPart)
+				}
+
+				if i < len(words)-1 {
+					b.fpdf.Write(5, " ")
+				}
+			}
+			b.fpdf.Ln(5)
+		}
+		b.fpdf.Ln(10)
+	}
+}
+
+func (b *BionicPdfWriter) Save(out io.Writer) error {
+	return b.fpdf.Output(out)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/ledongthuc/pdf"
+
+	"github.com/sponkurtus2/goBooksBold/internal/bionic"
+)
+
+// buildTestPdf generates a minimal one-page PDF containing text, for use
+// as test input.
+func buildTestPdf(text string) ([]byte, error) {
+	pdfDoc := gofpdf.New("P", "mm", "A4", "")
+	pdfDoc.AddPage()
+	pdfDoc.SetFont("Arial", "", 12)
+	pdfDoc.Write(5, text)
+
+	var buf bytes.Buffer
+	if err := pdfDoc.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// extractPdfText reads back the plain text of a generated PDF, the same
+// way handlePdfUpload does.
+func extractPdfText(pdfBytes []byte) (string, error) {
+	tmp, err := os.CreateTemp("", "booksbold_test_*.pdf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(pdfBytes); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	tmp.Close()
+
+	f, reader, err := pdf.Open(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	content, err := bionic.ReadPdfContent(context.Background(), reader)
+	if err != nil {
+		return "", err
+	}
+	return content.String(), nil
+}
+
+// TestUploadHandlerParallelRequestsDoNotLeak fires many simultaneous
+// uploads, each with a unique marker word, and checks that every
+// response contains only its own marker. With the old package-level
+// pdfContent builder, concurrent requests would stomp on each other.
+func TestUploadHandlerParallelRequestsDoNotLeak(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(uploadHandler))
+	defer server.Close()
+
+	const n = 8
+	marker := func(i int) string { return fmt.Sprintf("UNIQUEMARKER%d", i) }
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			pdfBytes, err := buildTestPdf(marker(i))
+			if err != nil {
+				errs[i] = fmt.Errorf("building test pdf: %w", err)
+				return
+			}
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			part, err := mw.CreateFormFile("pdfFile", "book.pdf")
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if _, err := part.Write(pdfBytes); err != nil {
+				errs[i] = err
+				return
+			}
+			if err := mw.Close(); err != nil {
+				errs[i] = err
+				return
+			}
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, &body)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer resp.Body.Close()
+
+			respBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				errs[i] = fmt.Errorf("status %d: %s", resp.StatusCode, respBytes)
+				return
+			}
+
+			text, err := extractPdfText(respBytes)
+			if err != nil {
+				errs[i] = fmt.Errorf("reading generated pdf: %w", err)
+				return
+			}
+
+			// BionicPdfWriter writes a word's bold and regular portions as
+			// separate fpdf.Write calls in different fonts, and
+			// ledongthuc/pdf's GetPlainText inserts a space between the
+			// resulting text-show operations when reading it back, so a
+			// round-tripped marker can come back as e.g. "UNIQU EMARKER0".
+			// Strip whitespace from both sides before comparing so the
+			// check is about which marker is present, not how it was
+			// paginated.
+			squashed := stripWhitespace(text)
+
+			if !strings.Contains(squashed, marker(i)) {
+				errs[i] = fmt.Errorf("response missing its own marker %q", marker(i))
+				return
+			}
+			for j := 0; j < n; j++ {
+				if j == i {
+					continue
+				}
+				if strings.Contains(squashed, marker(j)) {
+					errs[i] = fmt.Errorf("response leaked marker %q from a different request", marker(j))
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("request %d: %v", i, err)
+		}
+	}
+}
+
+// stripWhitespace removes spaces, tabs, and newlines, so text reconstructed
+// from a PDF's separate bold/regular text-show operations can be compared
+// without caring about the whitespace gofpdf/pdf insert between them.
+func stripWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jobProgress is the current/total page count for one in-flight
+// conversion, broadcast to any SSE listeners via waitCh.
+type jobProgress struct {
+	mu      sync.Mutex
+	current int
+	total   int
+	done    bool
+	waitCh  chan struct{}
+}
+
+func newJobProgress() *jobProgress {
+	return &jobProgress{waitCh: make(chan struct{})}
+}
+
+func (jp *jobProgress) update(current, total int) {
+	jp.mu.Lock()
+	jp.current, jp.total = current, total
+	old := jp.waitCh
+	jp.waitCh = make(chan struct{})
+	jp.mu.Unlock()
+	close(old)
+}
+
+func (jp *jobProgress) finish() {
+	jp.mu.Lock()
+	jp.done = true
+	old := jp.waitCh
+	jp.waitCh = make(chan struct{})
+	jp.mu.Unlock()
+	close(old)
+}
+
+func (jp *jobProgress) snapshot() (current, total int, done bool, waitCh chan struct{}) {
+	jp.mu.Lock()
+	defer jp.mu.Unlock()
+	return jp.current, jp.total, jp.done, jp.waitCh
+}
+
+var (
+	progressJobsMu sync.Mutex
+	progressJobs   = map[string]*jobProgress{}
+)
+
+// registerJobProgress creates (or replaces) the progress tracker for
+// jobID. Call the returned cleanup func once the conversion finishes, to
+// mark it done and stop tracking it.
+func registerJobProgress(jobID string) (jp *jobProgress, cleanup func()) {
+	jp = newJobProgress()
+
+	progressJobsMu.Lock()
+	progressJobs[jobID] = jp
+	progressJobsMu.Unlock()
+
+	return jp, func() {
+		jp.finish()
+		progressJobsMu.Lock()
+		delete(progressJobs, jobID)
+		progressJobsMu.Unlock()
+	}
+}
+
+func lookupJobProgress(jobID string) (*jobProgress, bool) {
+	progressJobsMu.Lock()
+	defer progressJobsMu.Unlock()
+	jp, ok := progressJobs[jobID]
+	return jp, ok
+}
+
+// progressHandler serves Server-Sent Events reporting a conversion's
+// current/total page as it runs, so the frontend can show progress on
+// multi-hundred-page PDFs. Clients connect with GET /progress?job=<id>,
+// using the same jobId they submitted their upload with.
+func progressHandler(w http.ResponseWriter, r *http.Request) {
+	jobID := r.URL.Query().Get("job")
+	if jobID == "" {
+		http.Error(w, "Missing job parameter", http.StatusBadRequest)
+		return
+	}
+
+	jp, ok := lookupJobProgress(jobID)
+	if !ok {
+		http.Error(w, "Unknown or already-finished job", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for {
+		current, total, done, waitCh := jp.snapshot()
+		fmt.Fprintf(w, "data: {\"current\":%d,\"total\":%d,\"done\":%t}\n\n", current, total, done)
+		flusher.Flush()
+
+		if done {
+			return
+		}
+
+		select {
+		case <-waitCh:
+		case <-r.Context().Done():
+			return
+		case <-time.After(30 * time.Second):
+			// heartbeat so intermediaries don't time out an idle connection
+		}
+	}
+}
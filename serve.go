@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"html/template"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/sponkurtus2/goBooksBold/internal/bionic"
+)
+
+const (
+	// defaultMaxConcurrentUploads bounds how many conversions run at
+	// once; override with the MAX_CONCURRENT_UPLOADS env var.
+	defaultMaxConcurrentUploads = 4
+	// defaultUploadTimeout bounds how long a single conversion may run
+	// before its context is cancelled.
+	defaultUploadTimeout = 60 * time.Second
+	// defaultMaxUploadBytes bounds the size of a single upload; override
+	// with the MAX_UPLOAD_BYTES env var.
+	defaultMaxUploadBytes = 500 << 20 // 500MB
+)
+
+// uploadSlots caps the number of conversions running concurrently, so a
+// burst of large uploads can't exhaust memory or CPU.
+var uploadSlots = make(chan struct{}, maxConcurrentUploads())
+
+func maxConcurrentUploads() int {
+	if v := os.Getenv("MAX_CONCURRENT_UPLOADS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultMaxConcurrentUploads
+}
+
+func maxUploadBytes() int64 {
+	if v := os.Getenv("MAX_UPLOAD_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultMaxUploadBytes
+}
+
+//go:embed template/index.html
+var content embed.FS
+
+// tpl is parsed lazily by runServe, not at package init: package main also
+// backs the convert subcommand, which never serves the upload page and
+// shouldn't fail to start just because template/index.html is missing.
+var tpl *template.Template
+
+func loadTemplate() {
+	data, err := content.ReadFile("template/index.html")
+	if err != nil {
+		log.Fatalf("Failed to read embedded page.html: %v", err)
+	}
+	tpl = template.Must(template.New("page.html").Parse(string(data)))
+}
+
+func uploadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		tpl.Execute(w, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), defaultUploadTimeout)
+	defer cancel()
+
+	select {
+	case uploadSlots <- struct{}{}:
+		defer func() { <-uploadSlots }()
+	case <-ctx.Done():
+		http.Error(w, "Server is busy, please try again", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes())
+
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil {
+		http.Error(w, "Upload too large or malformed", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if hocrHeaders := r.MultipartForm.File["hocrFile"]; len(hocrHeaders) == 1 {
+		handleHocrUpload(ctx, w, r, hocrHeaders[0])
+		return
+	}
+
+	handlePdfUpload(ctx, w, r)
+}
+
+// handlePdfUpload is the original behavior: a regular PDF is uploaded,
+// its text extracted, and a bionic-reading PDF written back. content is
+// scoped to this request, so it never leaks into a concurrent upload.
+func handlePdfUpload(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("pdfFile")
+	if err != nil {
+		http.Error(w, "Unable to get file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// PDF-ness isn't checked by content type here: it's client-supplied
+	// and unreliable (e.g. multipart.Writer.CreateFormFile sends
+	// application/octet-stream). pdf.Open below already rejects anything
+	// that isn't a real PDF.
+	tmpFile, err := os.CreateTemp("", "upload_*.pdf")
+	if err != nil {
+		http.Error(w, "Unable to create temp file", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, file); err != nil {
+		http.Error(w, "Unable to write temp file", http.StatusInternalServerError)
+		return
+	}
+
+	f, rr, err := pdf.Open(tmpFile.Name())
+	if err != nil {
+		http.Error(w, "Unable to open PDF", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	pageContent, err := bionic.ReadPdfContent(ctx, rr)
+	if err != nil {
+		http.Error(w, "Unable to read PDF content: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	onProgress, progressDone := progressReporterFor(r)
+	defer progressDone()
+
+	fixation := bionic.NewFixationConfig(bionic.ParseFixationLevel(r.URL.Query().Get("fixation")))
+	cw := &countingResponseWriter{ResponseWriter: w}
+	setPdfResponseHeaders(w)
+	if err := bionic.RenderPdf(ctx, bionic.NewBionicPdfWriter(*pageContent, fixation), 1, cw, onProgress); err != nil {
+		if cw.written == 0 {
+			http.Error(w, "Unable to generate PDF", http.StatusInternalServerError)
+		} else {
+			log.Printf("Error streaming generated PDF after %d bytes: %v", cw.written, err)
+		}
+		return
+	}
+}
+
+// handleHocrUpload parses an uploaded hOCR document plus its page images
+// and writes back a searchable PDF: each page image rendered at true
+// size with the OCR words drawn on top in invisible text.
+func handleHocrUpload(ctx context.Context, w http.ResponseWriter, r *http.Request, hocrHeader *multipart.FileHeader) {
+	hocrFile, err := hocrHeader.Open()
+	if err != nil {
+		http.Error(w, "Unable to read hOCR file", http.StatusBadRequest)
+		return
+	}
+	defer hocrFile.Close()
+
+	pages, err := bionic.ParseHocr(hocrFile)
+	if err != nil {
+		http.Error(w, "Unable to parse hOCR file", http.StatusBadRequest)
+		return
+	}
+
+	imageHeaders := r.MultipartForm.File["images"]
+	if len(imageHeaders) != len(pages) {
+		http.Error(w, "Number of page images must match number of hOCR pages", http.StatusBadRequest)
+		return
+	}
+
+	images := make([]bionic.HocrPageImage, len(imageHeaders))
+	for i, imgHeader := range imageHeaders {
+		imgFile, err := imgHeader.Open()
+		if err != nil {
+			http.Error(w, "Unable to read page image", http.StatusBadRequest)
+			return
+		}
+
+		data, err := io.ReadAll(imgFile)
+		imgFile.Close()
+		if err != nil {
+			http.Error(w, "Unable to read page image", http.StatusInternalServerError)
+			return
+		}
+
+		images[i] = bionic.HocrPageImage{
+			ContentType: imgHeader.Header.Get("Content-Type"),
+			Bytes:       data,
+		}
+	}
+
+	onProgress, progressDone := progressReporterFor(r)
+	defer progressDone()
+
+	cw := &countingResponseWriter{ResponseWriter: w}
+	setPdfResponseHeaders(w)
+	if err := bionic.RenderPdf(ctx, bionic.NewHocrPdfWriter(pages, images), len(pages), cw, onProgress); err != nil {
+		if cw.written == 0 {
+			http.Error(w, "Unable to generate PDF", http.StatusInternalServerError)
+		} else {
+			log.Printf("Error streaming generated PDF after %d bytes: %v", cw.written, err)
+		}
+		return
+	}
+}
+
+// countingResponseWriter tracks how many bytes have been written to an
+// http.ResponseWriter, so a failed RenderPdf can tell whether it's still
+// safe to send an error status (nothing written yet) or whether the
+// response is already committed and the failure can only be logged.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	written int64
+}
+
+func (cw *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := cw.ResponseWriter.Write(p)
+	cw.written += int64(n)
+	return n, err
+}
+
+// setPdfResponseHeaders prepares w for a streamed PDF body: the size
+// isn't known up front, so the response is chunked rather than
+// Content-Length-delimited.
+func setPdfResponseHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"book.pdf\"")
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// progressReporterFor registers a progress tracker for the request's
+// jobId form value, if one was given, and returns an onProgress callback
+// for bionic.RenderPdf that keeps it updated, plus a done func the caller
+// must defer to retire the tracker. done is safe to call exactly once and
+// must run on every exit path (success, error, or cancellation) so a
+// failed conversion doesn't leave its job stuck "in progress" forever. If
+// no jobId was given, onProgress is nil and done is a no-op.
+func progressReporterFor(r *http.Request) (onProgress func(current, total int), done func()) {
+	jobID := r.FormValue("jobId")
+	if jobID == "" {
+		return nil, func() {}
+	}
+
+	jp, cleanup := registerJobProgress(jobID)
+
+	return func(current, total int) {
+		jp.update(current, total)
+	}, cleanup
+}
+
+// runServe starts the HTTP upload server. It blocks until the server
+// exits or fails to start.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	portFlag := fs.String("port", "", "port to listen on (default: $PORT or 8080)")
+	fs.Parse(args)
+
+	loadTemplate()
+
+	http.HandleFunc("/", uploadHandler)
+	http.HandleFunc("/progress", progressHandler)
+
+	port := *portFlag
+	if port == "" {
+		port = os.Getenv("PORT")
+	}
+	if port == "" {
+		port = "8080"
+	}
+
+	log.Println("Server listening on http://localhost:" + port)
+	err := http.ListenAndServe(":"+port, nil)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal("Error starting server:", err)
+	}
+}